@@ -0,0 +1,147 @@
+// Command aricanduva is a small CLI around the go-storage S3 wrapper used
+// throughout this repo's examples.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"codeberg.org/gruf/go-storage/s3"
+
+	"github.com/bltavares/aricanduva/pkg/lifecycle"
+	"github.com/bltavares/aricanduva/pkg/query"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "select":
+		err = runSelect(os.Args[2:])
+	case "lifecycle":
+		err = runLifecycle(os.Args[2:])
+	default:
+		usage()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "aricanduva:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage: aricanduva <command> [flags]
+
+Commands:
+  select      run an S3 Select query against a stored object
+  lifecycle   manage bucket lifecycle rules`)
+}
+
+func openStorage(endpoint, bucket, accessKeyID, secretAccessKey string, useSSL bool) (*s3.Storage, error) {
+	storage, err := s3.Open(endpoint, bucket, &s3.Config{
+		CoreOpts: minio.Options{
+			Creds:  credentials.NewStaticV4(accessKeyID, secretAccessKey, ""),
+			Secure: useSSL,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("open storage: %w", err)
+	}
+	return storage, nil
+}
+
+func runLifecycle(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("lifecycle: expected a subcommand (apply)")
+	}
+
+	switch args[0] {
+	case "apply":
+		return runLifecycleApply(args[1:])
+	default:
+		return fmt.Errorf("lifecycle: unknown subcommand %q", args[0])
+	}
+}
+
+func runLifecycleApply(args []string) error {
+	fs := flag.NewFlagSet("lifecycle apply", flag.ExitOnError)
+	endpoint := fs.String("endpoint", "localhost:3000", "S3 endpoint")
+	accessKeyID := fs.String("access-key", os.Getenv("ARICANDUVA_ACCESS_KEY"), "S3 access key ID")
+	secretAccessKey := fs.String("secret-key", os.Getenv("ARICANDUVA_SECRET_KEY"), "S3 secret access key")
+	useSSL := fs.Bool("ssl", false, "use TLS when connecting to the endpoint")
+	bucket := fs.String("bucket", "", "bucket to apply the lifecycle configuration to")
+	file := fs.String("f", "", "path to a YAML lifecycle rules file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *bucket == "" || *file == "" {
+		return fmt.Errorf("lifecycle apply: --bucket and -f are required")
+	}
+
+	cfg, err := lifecycle.Load(*file)
+	if err != nil {
+		return err
+	}
+
+	storage, err := openStorage(*endpoint, *bucket, *accessKeyID, *secretAccessKey, *useSSL)
+	if err != nil {
+		return fmt.Errorf("lifecycle apply: %w", err)
+	}
+
+	if current, err := lifecycle.Fetch(context.Background(), storage); err == nil {
+		plan := lifecycle.Diff(current, cfg)
+		fmt.Printf("plan: +%d rule(s), -%d rule(s), %d unchanged\n", len(plan.Added), len(plan.Removed), len(plan.Unchanged))
+	}
+
+	return lifecycle.Apply(context.Background(), storage, cfg)
+}
+
+func runSelect(args []string) error {
+	fs := flag.NewFlagSet("select", flag.ExitOnError)
+	endpoint := fs.String("endpoint", "localhost:3000", "S3 endpoint")
+	accessKeyID := fs.String("access-key", os.Getenv("ARICANDUVA_ACCESS_KEY"), "S3 access key ID")
+	secretAccessKey := fs.String("secret-key", os.Getenv("ARICANDUVA_SECRET_KEY"), "S3 secret access key")
+	useSSL := fs.Bool("ssl", false, "use TLS when connecting to the endpoint")
+	bucket := fs.String("bucket", "", "bucket containing the object")
+	key := fs.String("key", "", "key of the object to query")
+	expr := fs.String("expr", "", `SQL-style SELECT statement, e.g. "SELECT s.* FROM S3Object s WHERE s.foo > 10"`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *bucket == "" || *key == "" || *expr == "" {
+		return fmt.Errorf("select: --bucket, --key and --expr are required")
+	}
+
+	ctx := context.Background()
+
+	storage, err := openStorage(*endpoint, *bucket, *accessKeyID, *secretAccessKey, *useSSL)
+	if err != nil {
+		return fmt.Errorf("select: %w", err)
+	}
+
+	results, err := query.New(storage).Query(ctx, *key, *expr, query.Options{
+		InputFormat:  query.FormatCSV,
+		OutputFormat: query.FormatCSV,
+	})
+	if err != nil {
+		return err
+	}
+	defer results.Close()
+
+	_, err = io.Copy(os.Stdout, results)
+	return err
+}
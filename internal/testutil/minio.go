@@ -0,0 +1,102 @@
+// Package testutil provides shared MinIO container fixtures for the
+// container-backed tests in this repo's pkg/* packages.
+package testutil
+
+import (
+	"context"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"codeberg.org/gruf/go-storage/s3"
+)
+
+// Credentials used by every MinIO container started through StartMinio.
+const (
+	AccessKeyID     = "aricanduva"
+	SecretAccessKey = "aricanduva-test"
+)
+
+// TB is the subset of testing.T / testing.B that these fixtures need.
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...any)
+	Cleanup(func())
+}
+
+// StartMinio starts a MinIO container for the duration of the test or
+// benchmark, with any extra env vars merged in, and returns its endpoint.
+// The container is terminated automatically via tb.Cleanup.
+func StartMinio(tb TB, env map[string]string) string {
+	tb.Helper()
+	ctx := context.Background()
+
+	containerEnv := map[string]string{
+		"MINIO_ROOT_USER":     AccessKeyID,
+		"MINIO_ROOT_PASSWORD": SecretAccessKey,
+	}
+	for k, v := range env {
+		containerEnv[k] = v
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "minio/minio:latest",
+			ExposedPorts: []string{"9000/tcp"},
+			Env:          containerEnv,
+			Cmd:          []string{"server", "/data"},
+			WaitingFor:   wait.ForHTTP("/minio/health/ready").WithPort("9000/tcp"),
+		},
+		Started: true,
+	})
+	if err != nil {
+		tb.Fatalf("start minio container: %v", err)
+	}
+	tb.Cleanup(func() { _ = container.Terminate(ctx) })
+
+	endpoint, err := container.Endpoint(ctx, "")
+	if err != nil {
+		tb.Fatalf("get container endpoint: %v", err)
+	}
+	return endpoint
+}
+
+// NewClient returns a minio.Client for endpoint using the fixture credentials.
+func NewClient(tb TB, endpoint string) *minio.Client {
+	tb.Helper()
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(AccessKeyID, SecretAccessKey, ""),
+		Secure: false,
+	})
+	if err != nil {
+		tb.Fatalf("new minio client: %v", err)
+	}
+	return client
+}
+
+// OpenStorage opens an s3.Storage for bucket at endpoint using the fixture
+// credentials. It does not create the bucket; call MakeBucket, or create it
+// directly (e.g. with object locking), first.
+func OpenStorage(tb TB, endpoint, bucket string) *s3.Storage {
+	tb.Helper()
+	storage, err := s3.Open(endpoint, bucket, &s3.Config{
+		CoreOpts: minio.Options{
+			Creds:  credentials.NewStaticV4(AccessKeyID, SecretAccessKey, ""),
+			Secure: false,
+		},
+	})
+	if err != nil {
+		tb.Fatalf("open storage: %v", err)
+	}
+	return storage
+}
+
+// MakeBucket creates bucket through storage's client.
+func MakeBucket(tb TB, storage *s3.Storage, bucket string) {
+	tb.Helper()
+	if err := storage.Client().MakeBucket(context.Background(), bucket, minio.MakeBucketOptions{}); err != nil {
+		tb.Fatalf("make bucket: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+// Package batch uploads many small objects in a single request using
+// minio-go's snowball upload extension, falling back to sequential
+// PutObject calls when the endpoint doesn't support it.
+package batch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"path"
+
+	"github.com/minio/minio-go/v7"
+
+	"codeberg.org/gruf/go-storage/s3"
+)
+
+// BatchItem is a single object to include in a batch upload.
+type BatchItem struct {
+	Key         string
+	ContentType string
+	// Size is the item's size in bytes, or -1 if unknown.
+	Size   int64
+	Reader io.Reader
+}
+
+// Config controls when PutBatch attempts a snowball upload.
+type Config struct {
+	// SnowballThreshold is the minimum number of items required before a
+	// snowball upload is attempted. Defaults to 100.
+	SnowballThreshold int
+}
+
+func (c Config) threshold() int {
+	if c.SnowballThreshold <= 0 {
+		return 100
+	}
+	return c.SnowballThreshold
+}
+
+// PutBatch uploads items under prefix. When len(items) meets cfg's
+// snowball threshold, it packages them into a single TAR stream uploaded
+// via the snowball extension so the server extracts them in one request;
+// otherwise, and whenever the endpoint doesn't support snowball uploads, it
+// falls back to sequential PutObject calls.
+//
+// Support for snowball uploads is probed before any item's Reader is
+// touched: items[i].Reader is typically not seekable, so once the snowball
+// upload has started consuming it, a failed upload can't be retried
+// sequentially without re-reading already-drained readers.
+func PutBatch(ctx context.Context, storage *s3.Storage, prefix string, items []BatchItem, cfg Config) error {
+	if len(items) >= cfg.threshold() {
+		supported, err := supportsSnowball(ctx, storage)
+		if err != nil {
+			return err
+		}
+		if supported {
+			return putBatchSnowball(ctx, storage, prefix, items)
+		}
+		log.Printf("batch: endpoint does not support snowball uploads, falling back to sequential puts")
+	}
+	return putBatchSequential(ctx, storage, prefix, items)
+}
+
+// supportsSnowball probes whether the endpoint accepts snowball uploads by
+// sending an empty object stream, which never touches any caller-supplied
+// Reader.
+func supportsSnowball(ctx context.Context, storage *s3.Storage) (bool, error) {
+	empty := make(chan minio.SnowballObject)
+	close(empty)
+
+	err := storage.Client().PutObjectsSnowball(ctx, storage.Bucket(), minio.SnowballOptions{}, empty)
+	if err == nil {
+		return true, nil
+	}
+	if isUnsupported(err) {
+		return false, nil
+	}
+	return false, fmt.Errorf("batch: probe snowball support: %w", err)
+}
+
+func putBatchSnowball(ctx context.Context, storage *s3.Storage, prefix string, items []BatchItem) error {
+	objectsCh := make(chan minio.SnowballObject, len(items))
+	go func() {
+		defer close(objectsCh)
+		for _, item := range items {
+			objectsCh <- minio.SnowballObject{
+				Key:         path.Join(prefix, item.Key),
+				ContentType: item.ContentType,
+				Size:        item.Size,
+				Content:     item.Reader,
+			}
+		}
+	}()
+
+	if err := storage.Client().PutObjectsSnowball(ctx, storage.Bucket(), minio.SnowballOptions{}, objectsCh); err != nil {
+		return fmt.Errorf("batch: snowball upload: %w", err)
+	}
+	return nil
+}
+
+func putBatchSequential(ctx context.Context, storage *s3.Storage, prefix string, items []BatchItem) error {
+	for _, item := range items {
+		_, err := storage.PutObject(ctx, path.Join(prefix, item.Key), item.Reader, minio.PutObjectOptions{
+			ContentType: item.ContentType,
+		})
+		if err != nil {
+			return fmt.Errorf("batch: put %s: %w", item.Key, err)
+		}
+	}
+	return nil
+}
+
+func isUnsupported(err error) bool {
+	switch minio.ToErrorResponse(err).Code {
+	case "NotImplemented", "MethodNotAllowed":
+		return true
+	default:
+		return false
+	}
+}
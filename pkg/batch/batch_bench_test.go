@@ -0,0 +1,66 @@
+package batch_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bltavares/aricanduva/internal/testutil"
+	"github.com/bltavares/aricanduva/pkg/batch"
+)
+
+// items returns n small BatchItems whose content names prefix. Their keys
+// carry no prefix; each benchmark iteration uploads them under its own
+// prefix so repeated b.N runs don't collide on keys.
+func items(prefix string, n int) []batch.BatchItem {
+	out := make([]batch.BatchItem, n)
+	for i := range out {
+		content := fmt.Sprintf("object %d/%d from %s", i, n, prefix)
+		out[i] = batch.BatchItem{
+			Key:         fmt.Sprintf("item-%04d.txt", i),
+			ContentType: "text/plain",
+			Size:        int64(len(content)),
+			Reader:      strings.NewReader(content),
+		}
+	}
+	return out
+}
+
+const benchItemCount = 100
+
+func BenchmarkPutBatchSequential(b *testing.B) {
+	ctx := context.Background()
+
+	const bucket = "batch-bench-sequential"
+	endpoint := testutil.StartMinio(b, nil)
+	storage := testutil.OpenStorage(b, endpoint, bucket)
+	testutil.MakeBucket(b, storage, bucket)
+
+	cfg := batch.Config{SnowballThreshold: benchItemCount + 1} // force sequential fallback
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prefix := fmt.Sprintf("run-%d", i)
+		if err := batch.PutBatch(ctx, storage, prefix, items(prefix, benchItemCount), cfg); err != nil {
+			b.Fatalf("put batch: %v", err)
+		}
+	}
+}
+
+func BenchmarkPutBatchSnowball(b *testing.B) {
+	ctx := context.Background()
+
+	const bucket = "batch-bench-snowball"
+	endpoint := testutil.StartMinio(b, nil)
+	storage := testutil.OpenStorage(b, endpoint, bucket)
+	testutil.MakeBucket(b, storage, bucket)
+
+	cfg := batch.Config{SnowballThreshold: benchItemCount}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prefix := fmt.Sprintf("run-%d", i)
+		if err := batch.PutBatch(ctx, storage, prefix, items(prefix, benchItemCount), cfg); err != nil {
+			b.Fatalf("put batch: %v", err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Package encryption adds transparent client-side envelope encryption on
+// top of go-storage's s3.Storage. Each object gets its own AES-256-GCM data
+// key, which is wrapped by a KeyProvider and stored alongside the object in
+// user metadata.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+
+	"codeberg.org/gruf/go-storage/s3"
+)
+
+const (
+	metaDataKey = "Data-Key"
+	metaIV      = "Iv"
+
+	// defaultChunkSize matches the PutChunkSize used by the go-storage demo.
+	defaultChunkSize = 5 * 1024 * 1024 // 5MiB
+)
+
+// Config configures a Storage.
+type Config struct {
+	// Keys generates and unwraps per-object data keys.
+	Keys KeyProvider
+	// ChunkSize is the plaintext chunk size used to stream-encrypt objects.
+	// Defaults to 5MiB, matching PutChunkSize in the go-storage demo.
+	ChunkSize int
+}
+
+// Storage wraps an s3.Storage, transparently encrypting objects written
+// through PutObject and decrypting objects read through GetObject.
+type Storage struct {
+	*s3.Storage
+	keys      KeyProvider
+	chunkSize int
+}
+
+// Wrap returns a Storage that transparently encrypts/decrypts objects
+// stored through storage.
+func Wrap(storage *s3.Storage, cfg Config) *Storage {
+	chunkSize := cfg.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	return &Storage{Storage: storage, keys: cfg.Keys, chunkSize: chunkSize}
+}
+
+// PutObject encrypts r with a fresh per-object data key and uploads it,
+// storing the wrapped data key and IV in the object's user metadata.
+func (s *Storage) PutObject(ctx context.Context, key string, r io.Reader, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	dataKey, wrapped, err := s.keys.GenerateDataKey(ctx)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return minio.UploadInfo{}, fmt.Errorf("encryption: build object cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return minio.UploadInfo{}, fmt.Errorf("encryption: build object cipher: %w", err)
+	}
+
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return minio.UploadInfo{}, fmt.Errorf("encryption: generate iv: %w", err)
+	}
+
+	if opts.UserMetadata == nil {
+		opts.UserMetadata = map[string]string{}
+	}
+	opts.UserMetadata[metaDataKey] = base64.StdEncoding.EncodeToString(wrapped)
+	opts.UserMetadata[metaIV] = base64.StdEncoding.EncodeToString(iv)
+
+	pr, pw := io.Pipe()
+	go func() {
+		seal := func(nonce, plaintext []byte) []byte { return gcm.Seal(nil, nonce, plaintext, nil) }
+		pw.CloseWithError(encryptStream(pw, r, seal, iv, s.chunkSize))
+	}()
+
+	info, err := s.Storage.PutObject(ctx, key, pr, opts)
+	// PutObject can return before pr has been read to EOF (e.g. on error or
+	// ctx cancellation); close it so the encryptStream goroutine above isn't
+	// left blocked forever on pw.Write.
+	pr.CloseWithError(err)
+	if err != nil {
+		return info, fmt.Errorf("encryption: put %s: %w", key, err)
+	}
+	return info, nil
+}
+
+// GetObject downloads and transparently decrypts the object at key.
+func (s *Storage) GetObject(ctx context.Context, key string, opts minio.GetObjectOptions) (io.ReadCloser, error) {
+	obj, err := s.Storage.GetObject(ctx, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, fmt.Errorf("encryption: stat %s: %w", key, err)
+	}
+
+	// minio-go reports user metadata keys without the X-Amz-Meta- prefix.
+	wrapped, err := base64.StdEncoding.DecodeString(info.UserMetadata[metaDataKey])
+	if err != nil {
+		obj.Close()
+		return nil, fmt.Errorf("encryption: decode data key for %s: %w", key, err)
+	}
+	iv, err := base64.StdEncoding.DecodeString(info.UserMetadata[metaIV])
+	if err != nil {
+		obj.Close()
+		return nil, fmt.Errorf("encryption: decode iv for %s: %w", key, err)
+	}
+
+	dataKey, err := s.keys.UnwrapDataKey(ctx, wrapped)
+	if err != nil {
+		obj.Close()
+		return nil, fmt.Errorf("encryption: unwrap data key for %s: %w", key, err)
+	}
+
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		obj.Close()
+		return nil, fmt.Errorf("encryption: build object cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		obj.Close()
+		return nil, fmt.Errorf("encryption: build object cipher: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer obj.Close()
+		open := func(nonce, ciphertext []byte) ([]byte, error) { return gcm.Open(nil, nonce, ciphertext, nil) }
+		pw.CloseWithError(decryptStream(pw, obj, open, iv))
+	}()
+
+	return pr, nil
+}
+
+// GetObjectCiphertext downloads the object at key as stored, without
+// attempting to decrypt it. Presigned GET URLs obtained via
+// s.Storage.Client().PresignedGetObject bypass this wrapper entirely and
+// likewise return ciphertext.
+func (s *Storage) GetObjectCiphertext(ctx context.Context, key string, opts minio.GetObjectOptions) (io.ReadCloser, error) {
+	return s.Storage.GetObject(ctx, key, opts)
+}
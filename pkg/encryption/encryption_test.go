@@ -0,0 +1,120 @@
+package encryption_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/bltavares/aricanduva/internal/testutil"
+	"github.com/bltavares/aricanduva/pkg/encryption"
+)
+
+func TestPutObjectRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	const bucket = "encryption-test"
+	endpoint := testutil.StartMinio(t, nil)
+	storage := testutil.OpenStorage(t, endpoint, bucket)
+	testutil.MakeBucket(t, storage, bucket)
+
+	keys, err := encryption.NewStaticKeyProvider(bytes.Repeat([]byte{0x42}, 32))
+	if err != nil {
+		t.Fatalf("new key provider: %v", err)
+	}
+	enc := encryption.Wrap(storage, encryption.Config{Keys: keys})
+
+	const key = "secret.txt"
+	const plaintext = "this is a secret that should never be stored in the clear"
+
+	if _, err := enc.PutObject(ctx, key, bytes.NewBufferString(plaintext), minio.PutObjectOptions{
+		ContentType: "text/plain",
+	}); err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+
+	raw, err := enc.GetObjectCiphertext(ctx, key, minio.GetObjectOptions{})
+	if err != nil {
+		t.Fatalf("get object ciphertext: %v", err)
+	}
+	rawBytes, err := io.ReadAll(raw)
+	raw.Close()
+	if err != nil {
+		t.Fatalf("read raw bytes: %v", err)
+	}
+	if bytes.Contains(rawBytes, []byte(plaintext)) {
+		t.Errorf("expected raw stored bytes to not contain the plaintext, got %q", rawBytes)
+	}
+
+	decrypted, err := enc.GetObject(ctx, key, minio.GetObjectOptions{})
+	if err != nil {
+		t.Fatalf("get object: %v", err)
+	}
+	defer decrypted.Close()
+
+	got, err := io.ReadAll(decrypted)
+	if err != nil {
+		t.Fatalf("read decrypted bytes: %v", err)
+	}
+	if string(got) != plaintext {
+		t.Errorf("expected decrypted content %q, got %q", plaintext, got)
+	}
+}
+
+func TestGetObjectCiphertextOptsOutOfDecryption(t *testing.T) {
+	ctx := context.Background()
+
+	const bucket = "encryption-opt-out-test"
+	endpoint := testutil.StartMinio(t, nil)
+	storage := testutil.OpenStorage(t, endpoint, bucket)
+	testutil.MakeBucket(t, storage, bucket)
+
+	keys, err := encryption.NewStaticKeyProvider(bytes.Repeat([]byte{0x24}, 32))
+	if err != nil {
+		t.Fatalf("new key provider: %v", err)
+	}
+	enc := encryption.Wrap(storage, encryption.Config{Keys: keys})
+
+	const key = "opaque.bin"
+	const plaintext = "another secret"
+	if _, err := enc.PutObject(ctx, key, bytes.NewBufferString(plaintext), minio.PutObjectOptions{}); err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+
+	// A presigned GET URL bypasses the encryption wrapper entirely, so it
+	// still works for an encrypted object -- it just returns ciphertext.
+	u, err := storage.Client().PresignedGetObject(ctx, storage.Bucket(), key, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("presigned get object: %v", err)
+	}
+	resp, err := http.Get(u.String())
+	if err != nil {
+		t.Fatalf("fetch presigned url: %v", err)
+	}
+	defer resp.Body.Close()
+	presignedBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read presigned response: %v", err)
+	}
+	if string(presignedBytes) == plaintext {
+		t.Errorf("expected presigned GET to return ciphertext, got plaintext back")
+	}
+
+	raw, err := enc.GetObjectCiphertext(ctx, key, minio.GetObjectOptions{})
+	if err != nil {
+		t.Fatalf("get object ciphertext: %v", err)
+	}
+	defer raw.Close()
+
+	rawBytes, err := io.ReadAll(raw)
+	if err != nil {
+		t.Fatalf("read raw bytes: %v", err)
+	}
+	if string(rawBytes) == plaintext {
+		t.Errorf("expected ciphertext, got plaintext back")
+	}
+}
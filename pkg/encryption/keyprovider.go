@@ -0,0 +1,90 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// KeyProvider generates and unwraps per-object data keys. A KMS-backed
+// provider can implement this interface as a drop-in replacement for
+// StaticKeyProvider.
+type KeyProvider interface {
+	// GenerateDataKey returns a new plaintext data key and its wrapped
+	// (encrypted) form, to be stored alongside the object.
+	GenerateDataKey(ctx context.Context) (plaintext, wrapped []byte, err error)
+	// UnwrapDataKey decrypts a data key previously returned by GenerateDataKey.
+	UnwrapDataKey(ctx context.Context, wrapped []byte) (plaintext []byte, err error)
+}
+
+// dataKeySize is the size, in bytes, of generated AES-256 data keys.
+const dataKeySize = 32
+
+// StaticKeyProvider wraps data keys with a single local AES-256-GCM master
+// key. It is meant for development and single-node deployments; a future
+// KMS integration can implement KeyProvider instead.
+type StaticKeyProvider struct {
+	MasterKey [dataKeySize]byte
+}
+
+// NewStaticKeyProvider returns a StaticKeyProvider using masterKey, which
+// must be 32 bytes long.
+func NewStaticKeyProvider(masterKey []byte) (*StaticKeyProvider, error) {
+	if len(masterKey) != dataKeySize {
+		return nil, fmt.Errorf("encryption: master key must be %d bytes, got %d", dataKeySize, len(masterKey))
+	}
+	var p StaticKeyProvider
+	copy(p.MasterKey[:], masterKey)
+	return &p, nil
+}
+
+func (p *StaticKeyProvider) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(p.MasterKey[:])
+	if err != nil {
+		return nil, fmt.Errorf("encryption: build master cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// GenerateDataKey implements KeyProvider.
+func (p *StaticKeyProvider) GenerateDataKey(ctx context.Context) (plaintext, wrapped []byte, err error) {
+	plaintext = make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, plaintext); err != nil {
+		return nil, nil, fmt.Errorf("encryption: generate data key: %w", err)
+	}
+
+	gcm, err := p.gcm()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, fmt.Errorf("encryption: generate nonce: %w", err)
+	}
+
+	wrapped = gcm.Seal(nonce, nonce, plaintext, nil)
+	return plaintext, wrapped, nil
+}
+
+// UnwrapDataKey implements KeyProvider.
+func (p *StaticKeyProvider) UnwrapDataKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	gcm, err := p.gcm()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, fmt.Errorf("encryption: wrapped data key too short")
+	}
+	nonce, ciphertext := wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: unwrap data key: %w", err)
+	}
+	return plaintext, nil
+}
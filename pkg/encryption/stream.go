@@ -0,0 +1,86 @@
+package encryption
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Each chunk is framed as a 4-byte big-endian length prefix followed by that
+// many bytes of GCM-sealed ciphertext (plaintext chunk + 16-byte tag). The
+// nonce for chunk i is the stream IV with its last 8 bytes XORed with i, so a
+// single IV can be reused safely across all chunks of one object.
+
+func chunkNonce(iv []byte, index uint64) []byte {
+	nonce := make([]byte, len(iv))
+	copy(nonce, iv)
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], binary.BigEndian.Uint64(nonce[len(nonce)-8:])^index)
+	return nonce
+}
+
+func encryptStream(w io.Writer, r io.Reader, seal sealFunc, iv []byte, chunkSize int) error {
+	buf := make([]byte, chunkSize)
+	for index := uint64(0); ; index++ {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			ciphertext := seal(chunkNonce(iv, index), buf[:n])
+			if err := writeChunk(w, ciphertext); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("encryption: read plaintext: %w", err)
+		}
+	}
+}
+
+func decryptStream(w io.Writer, r io.Reader, open openFunc, iv []byte) error {
+	for index := uint64(0); ; index++ {
+		chunk, err := readChunk(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("encryption: read ciphertext: %w", err)
+		}
+
+		plaintext, err := open(chunkNonce(iv, index), chunk)
+		if err != nil {
+			return fmt.Errorf("encryption: decrypt chunk %d: %w", index, err)
+		}
+		if _, err := w.Write(plaintext); err != nil {
+			return err
+		}
+	}
+}
+
+type sealFunc func(nonce, plaintext []byte) []byte
+type openFunc func(nonce, ciphertext []byte) ([]byte, error)
+
+func writeChunk(w io.Writer, chunk []byte) error {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(chunk)))
+	if _, err := w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(chunk)
+	return err
+}
+
+func readChunk(r io.Reader) ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		return nil, err
+	}
+	chunk := make([]byte, binary.BigEndian.Uint32(length[:]))
+	if _, err := io.ReadFull(r, chunk); err != nil {
+		return nil, err
+	}
+	return chunk, nil
+}
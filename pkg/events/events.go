@@ -0,0 +1,176 @@
+// Package events subscribes to S3 bucket notifications via minio-go's
+// ListenBucketNotification and dispatches decoded records to pluggable
+// handlers.
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/notification"
+
+	"codeberg.org/gruf/go-storage/s3"
+)
+
+// EventRecord is a decoded notification.Event record.
+type EventRecord struct {
+	EventName   string
+	Bucket      string
+	Key         string
+	Size        int64
+	ETag        string
+	ContentType string
+	Time        time.Time
+}
+
+// Handler reacts to a single bucket notification record.
+type Handler interface {
+	Handle(ctx context.Context, rec EventRecord) error
+}
+
+// HandlerFunc adapts a plain function to a Handler.
+type HandlerFunc func(ctx context.Context, rec EventRecord) error
+
+func (f HandlerFunc) Handle(ctx context.Context, rec EventRecord) error { return f(ctx, rec) }
+
+func recordToEvent(rec notification.Event) (EventRecord, error) {
+	t, err := time.Parse(time.RFC3339, rec.EventTime)
+	if err != nil {
+		return EventRecord{}, fmt.Errorf("events: parse event time %q: %w", rec.EventTime, err)
+	}
+	return EventRecord{
+		EventName:   rec.EventName,
+		Bucket:      rec.S3.Bucket.Name,
+		Key:         rec.S3.Object.Key,
+		Size:        rec.S3.Object.Size,
+		ETag:        rec.S3.Object.ETag,
+		ContentType: rec.S3.Object.ContentType,
+		Time:        t,
+	}, nil
+}
+
+// backoff bounds reconnection to the notification stream.
+const (
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+)
+
+// Subscriber listens for bucket notifications and dispatches them to a set
+// of Handlers, reconnecting with exponential backoff when the stream drops.
+type Subscriber struct {
+	storage  *s3.Storage
+	prefix   string
+	suffix   string
+	events   []string
+	handlers []Handler
+}
+
+// Option configures a Subscriber.
+type Option func(*Subscriber)
+
+// WithPrefix restricts notifications to keys with the given prefix.
+func WithPrefix(prefix string) Option {
+	return func(s *Subscriber) { s.prefix = prefix }
+}
+
+// WithSuffix restricts notifications to keys with the given suffix.
+func WithSuffix(suffix string) Option {
+	return func(s *Subscriber) { s.suffix = suffix }
+}
+
+// WithEvents overrides the default event types subscribed to.
+func WithEvents(events ...string) Option {
+	return func(s *Subscriber) { s.events = events }
+}
+
+// WithHandler registers a handler that is invoked for every record received.
+func WithHandler(h Handler) Option {
+	return func(s *Subscriber) { s.handlers = append(s.handlers, h) }
+}
+
+// NewSubscriber returns a Subscriber over storage's bucket, by default
+// subscribed to object created, removed, and accessed events.
+func NewSubscriber(storage *s3.Storage, opts ...Option) *Subscriber {
+	s := &Subscriber{
+		storage: storage,
+		events:  []string{"s3:ObjectCreated:*", "s3:ObjectRemoved:*", "s3:ObjectAccessed:*"},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Run subscribes to the bucket's notifications and dispatches records to the
+// configured handlers until ctx is cancelled, reconnecting with exponential
+// backoff if the notification stream drops.
+func (s *Subscriber) Run(ctx context.Context) error {
+	backoff := minBackoff
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		attemptCtx, cancel := context.WithCancel(ctx)
+		notifCh := s.storage.Client().ListenBucketNotification(attemptCtx, s.storage.Bucket(), s.prefix, s.suffix, s.events)
+		if s.dispatch(ctx, notifCh) {
+			backoff = minBackoff
+		}
+		// Cancel this attempt's context so minio-go's internal retry
+		// goroutine for notifCh is forced to exit via its own ctx.Done()
+		// case, rather than being abandoned to retry forever alongside the
+		// new attempt we're about to start.
+		cancel()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		log.Printf("events: notification stream closed, reconnecting in %s", backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// dispatch reads notifCh until it closes or reports a stream error,
+// converting each record to an EventRecord and running it through every
+// registered handler. It reports whether at least one notification was
+// received without error.
+func (s *Subscriber) dispatch(ctx context.Context, notifCh <-chan notification.Info) bool {
+	ok := false
+	for notif := range notifCh {
+		if notif.Err != nil {
+			log.Printf("events: notification stream error: %v", notif.Err)
+			return ok
+		}
+		ok = true
+		for _, rec := range notif.Records {
+			event, err := recordToEvent(rec)
+			if err != nil {
+				log.Printf("events: %v", err)
+				continue
+			}
+			for _, h := range s.handlers {
+				if err := h.Handle(ctx, event); err != nil {
+					log.Printf("events: handler error for %s: %v", event.Key, err)
+				}
+			}
+		}
+	}
+	return ok
+}
+
+// nextBackoff doubles current, capped at maxBackoff.
+func nextBackoff(current time.Duration) time.Duration {
+	next := current * 2
+	if next > maxBackoff {
+		next = maxBackoff
+	}
+	return next
+}
@@ -0,0 +1,158 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/notification"
+)
+
+func newEvent(t *testing.T, eventTime string) notification.Event {
+	t.Helper()
+	var ev notification.Event
+	ev.EventName = "s3:ObjectCreated:Put"
+	ev.EventTime = eventTime
+	ev.S3.Bucket.Name = "bucket"
+	ev.S3.Object.Key = "key"
+	ev.S3.Object.Size = 42
+	ev.S3.Object.ETag = "etag"
+	ev.S3.Object.ContentType = "text/plain"
+	return ev
+}
+
+func TestRecordToEvent(t *testing.T) {
+	ev := newEvent(t, "2024-01-02T03:04:05Z")
+
+	rec, err := recordToEvent(ev)
+	if err != nil {
+		t.Fatalf("recordToEvent: %v", err)
+	}
+	if rec.Key != "key" || rec.Bucket != "bucket" || rec.Size != 42 {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+	want, _ := time.Parse(time.RFC3339, "2024-01-02T03:04:05Z")
+	if !rec.Time.Equal(want) {
+		t.Errorf("expected time %v, got %v", want, rec.Time)
+	}
+}
+
+// TestRecordToEventParseError guards against silently swallowing a
+// malformed EventTime, which previously left Time at its zero value with
+// no indication anything had gone wrong.
+func TestRecordToEventParseError(t *testing.T) {
+	ev := newEvent(t, "not-a-timestamp")
+
+	if _, err := recordToEvent(ev); err == nil {
+		t.Fatal("expected an error for an unparseable event time")
+	}
+}
+
+type fakeHandler struct {
+	records []EventRecord
+	err     error
+}
+
+func (h *fakeHandler) Handle(ctx context.Context, rec EventRecord) error {
+	h.records = append(h.records, rec)
+	return h.err
+}
+
+func TestSubscriberDispatch(t *testing.T) {
+	h := &fakeHandler{}
+	s := NewSubscriber(nil, WithHandler(h))
+
+	notifCh := make(chan notification.Info, 2)
+	notifCh <- notification.Info{Records: []notification.Event{newEvent(t, "2024-01-02T03:04:05Z")}}
+	notifCh <- notification.Info{Records: []notification.Event{newEvent(t, "2024-01-02T03:04:06Z")}}
+	close(notifCh)
+
+	if ok := s.dispatch(context.Background(), notifCh); !ok {
+		t.Fatal("expected dispatch to report at least one record received")
+	}
+	if len(h.records) != 2 {
+		t.Fatalf("expected 2 records dispatched to handler, got %d", len(h.records))
+	}
+}
+
+// TestSubscriberDispatchStreamError guards against the stream-error case
+// being mistaken for a clean close: dispatch must stop and report false as
+// soon as it sees notif.Err, without touching handlers for later sends.
+func TestSubscriberDispatchStreamError(t *testing.T) {
+	h := &fakeHandler{}
+	s := NewSubscriber(nil, WithHandler(h))
+
+	notifCh := make(chan notification.Info, 2)
+	notifCh <- notification.Info{Err: errors.New("boom")}
+	notifCh <- notification.Info{Records: []notification.Event{newEvent(t, "2024-01-02T03:04:05Z")}}
+	close(notifCh)
+
+	if ok := s.dispatch(context.Background(), notifCh); ok {
+		t.Fatal("expected dispatch to report no records received after a stream error")
+	}
+	if len(h.records) != 0 {
+		t.Fatalf("expected no records dispatched, got %d", len(h.records))
+	}
+}
+
+func TestNextBackoff(t *testing.T) {
+	if got := nextBackoff(minBackoff); got != 2*minBackoff {
+		t.Errorf("expected backoff to double, got %s", got)
+	}
+	if got := nextBackoff(maxBackoff); got != maxBackoff {
+		t.Errorf("expected backoff to stay capped at %s, got %s", maxBackoff, got)
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := LogHandler{Logger: log.New(&buf, "", 0)}
+
+	if err := h.Handle(context.Background(), EventRecord{EventName: "s3:ObjectCreated:Put", Bucket: "b", Key: "k", Size: 3}); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("b/k")) {
+		t.Errorf("expected log output to mention the object, got %q", buf.String())
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var gotBody EventRecord
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("expected JSON content type, got %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("decode webhook payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	h := WebhookHandler{URL: server.URL}
+	rec := EventRecord{EventName: "s3:ObjectCreated:Put", Bucket: "b", Key: "k", Size: 3}
+	if err := h.Handle(context.Background(), rec); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if gotBody != rec {
+		t.Errorf("expected webhook payload %+v, got %+v", rec, gotBody)
+	}
+}
+
+func TestWebhookHandlerErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	h := WebhookHandler{URL: server.URL}
+	if err := h.Handle(context.Background(), EventRecord{}); err == nil {
+		t.Fatal("expected an error for a non-2xx webhook response")
+	}
+}
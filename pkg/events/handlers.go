@@ -0,0 +1,95 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"codeberg.org/gruf/go-storage/s3"
+)
+
+// DefaultPresignTTL matches the TTL used by the existing PresignedGetObject
+// demo code path.
+const DefaultPresignTTL = time.Hour * 24
+
+// LogHandler logs every record it receives.
+type LogHandler struct {
+	Logger *log.Logger
+}
+
+// Handle implements Handler.
+func (h LogHandler) Handle(ctx context.Context, rec EventRecord) error {
+	logger := h.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	logger.Printf("events: %s %s/%s (%d bytes)", rec.EventName, rec.Bucket, rec.Key, rec.Size)
+	return nil
+}
+
+// PresignHandler re-signs a fresh GET URL for the affected object, reusing
+// the same PresignedGetObject code path as the go-storage demo.
+type PresignHandler struct {
+	Storage *s3.Storage
+	TTL     time.Duration
+}
+
+// Handle implements Handler.
+func (h PresignHandler) Handle(ctx context.Context, rec EventRecord) error {
+	ttl := h.TTL
+	if ttl == 0 {
+		ttl = DefaultPresignTTL
+	}
+
+	u, err := h.Storage.Client().PresignedGetObject(ctx, rec.Bucket, rec.Key, ttl, url.Values{
+		"response-content-type": []string{mime.TypeByExtension(path.Ext(rec.Key))},
+	})
+	if err != nil {
+		return fmt.Errorf("events: presign %s: %w", rec.Key, err)
+	}
+	log.Printf("events: presigned %s -> %s", rec.Key, u)
+	return nil
+}
+
+// WebhookHandler forwards every record as JSON to a configured HTTP endpoint.
+type WebhookHandler struct {
+	URL    string
+	Client *http.Client
+}
+
+// Handle implements Handler.
+func (h WebhookHandler) Handle(ctx context.Context, rec EventRecord) error {
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("events: marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("events: build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("events: post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("events: webhook %s returned %s", h.URL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+// Package lifecycle lets operators define S3 bucket lifecycle rules in a
+// YAML file and apply them through the underlying minio-go client.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	miniolc "github.com/minio/minio-go/v7/pkg/lifecycle"
+	"gopkg.in/yaml.v3"
+
+	"codeberg.org/gruf/go-storage/s3"
+)
+
+// lifecycleDateLayout is the RFC3339 variant S3 lifecycle rules expect Date
+// fields to be formatted in.
+const lifecycleDateLayout = "2006-01-02T15:04:05.000Z"
+
+// Transition moves current object versions to another storage class after
+// Days, or at Date, if set. Days and Date are mutually exclusive.
+type Transition struct {
+	Days         int    `yaml:"days,omitempty"`
+	Date         string `yaml:"date,omitempty"`
+	StorageClass string `yaml:"storage_class"`
+}
+
+// Expiration removes current object versions after Days, or at Date, if
+// set. Days and Date are mutually exclusive.
+type Expiration struct {
+	Days int    `yaml:"days,omitempty"`
+	Date string `yaml:"date,omitempty"`
+}
+
+// NoncurrentVersionExpiration removes noncurrent object versions after
+// NoncurrentDays.
+type NoncurrentVersionExpiration struct {
+	NoncurrentDays int `yaml:"noncurrent_days"`
+}
+
+// AbortIncompleteMultipartUpload aborts multipart uploads left incomplete
+// for more than DaysAfterInitiation.
+type AbortIncompleteMultipartUpload struct {
+	DaysAfterInitiation int `yaml:"days_after_initiation"`
+}
+
+// Rule is a single bucket lifecycle rule.
+type Rule struct {
+	ID                          string                          `yaml:"id"`
+	Prefix                      string                          `yaml:"prefix,omitempty"`
+	Status                      string                          `yaml:"status"` // "Enabled" or "Disabled"
+	Transition                  *Transition                     `yaml:"transition,omitempty"`
+	Expiration                  *Expiration                     `yaml:"expiration,omitempty"`
+	NoncurrentVersionExpiration *NoncurrentVersionExpiration    `yaml:"noncurrent_version_expiration,omitempty"`
+	AbortIncompleteMultipart    *AbortIncompleteMultipartUpload `yaml:"abort_incomplete_multipart_upload,omitempty"`
+}
+
+// Config is a bucket lifecycle configuration as loaded from YAML.
+type Config struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Load reads and parses a lifecycle configuration from a YAML file.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lifecycle: read %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("lifecycle: parse %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Apply replaces the bucket's lifecycle configuration with cfg.
+func Apply(ctx context.Context, storage *s3.Storage, cfg *Config) error {
+	minioCfg, err := cfg.toMinio()
+	if err != nil {
+		return err
+	}
+
+	if err := storage.Client().SetBucketLifecycle(ctx, storage.Bucket(), minioCfg); err != nil {
+		return fmt.Errorf("lifecycle: apply to %s: %w", storage.Bucket(), err)
+	}
+	return nil
+}
+
+// Fetch returns the bucket's current lifecycle configuration.
+func Fetch(ctx context.Context, storage *s3.Storage) (*Config, error) {
+	lc, err := storage.Client().GetBucketLifecycle(ctx, storage.Bucket())
+	if err != nil {
+		return nil, fmt.Errorf("lifecycle: fetch from %s: %w", storage.Bucket(), err)
+	}
+	return fromMinio(lc), nil
+}
+
+// Plan describes the rules that would change if desired were applied over current.
+type Plan struct {
+	Added     []string
+	Removed   []string
+	Unchanged []string
+}
+
+// Diff compares current against desired and returns the rule IDs that would
+// be added, removed, or left unchanged.
+func Diff(current, desired *Config) Plan {
+	byID := func(cfg *Config) map[string]Rule {
+		m := make(map[string]Rule, len(cfg.Rules))
+		for _, r := range cfg.Rules {
+			m[r.ID] = r
+		}
+		return m
+	}
+
+	currentRules := byID(current)
+	desiredRules := byID(desired)
+
+	var plan Plan
+	for id, want := range desiredRules {
+		if have, ok := currentRules[id]; !ok {
+			plan.Added = append(plan.Added, id)
+		} else if !reflect.DeepEqual(have, want) {
+			// Rule's optional fields are pointers; DeepEqual compares the
+			// pointed-to values rather than the pointers themselves.
+			plan.Added = append(plan.Added, id)
+		} else {
+			plan.Unchanged = append(plan.Unchanged, id)
+		}
+	}
+	for id := range currentRules {
+		if _, ok := desiredRules[id]; !ok {
+			plan.Removed = append(plan.Removed, id)
+		}
+	}
+	return plan
+}
+
+func (c *Config) toMinio() (*miniolc.Configuration, error) {
+	out := &miniolc.Configuration{}
+	for _, r := range c.Rules {
+		rule := miniolc.Rule{
+			ID:     r.ID,
+			Status: r.Status,
+			RuleFilter: miniolc.Filter{
+				Prefix: r.Prefix,
+			},
+		}
+		if r.Transition != nil {
+			if r.Transition.Days != 0 && r.Transition.Date != "" {
+				return nil, fmt.Errorf("lifecycle: rule %q: transition days and date are mutually exclusive", r.ID)
+			}
+			rule.Transition.StorageClass = r.Transition.StorageClass
+			if r.Transition.Date != "" {
+				date, err := parseLifecycleDate(r.Transition.Date)
+				if err != nil {
+					return nil, fmt.Errorf("lifecycle: rule %q: %w", r.ID, err)
+				}
+				rule.Transition.Date = date
+			} else {
+				rule.Transition.Days = miniolc.ExpirationDays(r.Transition.Days)
+			}
+		}
+		if r.Expiration != nil {
+			if r.Expiration.Days != 0 && r.Expiration.Date != "" {
+				return nil, fmt.Errorf("lifecycle: rule %q: expiration days and date are mutually exclusive", r.ID)
+			}
+			if r.Expiration.Date != "" {
+				date, err := parseLifecycleDate(r.Expiration.Date)
+				if err != nil {
+					return nil, fmt.Errorf("lifecycle: rule %q: %w", r.ID, err)
+				}
+				rule.Expiration.Date = date
+			} else {
+				rule.Expiration.Days = miniolc.ExpirationDays(r.Expiration.Days)
+			}
+		}
+		if r.NoncurrentVersionExpiration != nil {
+			rule.NoncurrentVersionExpiration = miniolc.NoncurrentVersionExpiration{
+				NoncurrentDays: miniolc.ExpirationDays(r.NoncurrentVersionExpiration.NoncurrentDays),
+			}
+		}
+		if r.AbortIncompleteMultipart != nil {
+			rule.AbortIncompleteMultipartUpload = miniolc.AbortIncompleteMultipartUpload{
+				DaysAfterInitiation: miniolc.ExpirationDays(r.AbortIncompleteMultipart.DaysAfterInitiation),
+			}
+		}
+		out.Rules = append(out.Rules, rule)
+	}
+	return out, nil
+}
+
+func fromMinio(lc *miniolc.Configuration) *Config {
+	cfg := &Config{}
+	for _, rule := range lc.Rules {
+		r := Rule{
+			ID:     rule.ID,
+			Prefix: rule.RuleFilter.Prefix,
+			Status: rule.Status,
+		}
+		if rule.Transition.StorageClass != "" {
+			r.Transition = &Transition{StorageClass: rule.Transition.StorageClass}
+			if !rule.Transition.Date.IsZero() {
+				r.Transition.Date = formatLifecycleDate(rule.Transition.Date)
+			} else {
+				r.Transition.Days = int(rule.Transition.Days)
+			}
+		}
+		if rule.Expiration.Days > 0 || !rule.Expiration.Date.IsZero() {
+			r.Expiration = &Expiration{}
+			if !rule.Expiration.Date.IsZero() {
+				r.Expiration.Date = formatLifecycleDate(rule.Expiration.Date)
+			} else {
+				r.Expiration.Days = int(rule.Expiration.Days)
+			}
+		}
+		if rule.NoncurrentVersionExpiration.NoncurrentDays > 0 {
+			r.NoncurrentVersionExpiration = &NoncurrentVersionExpiration{
+				NoncurrentDays: int(rule.NoncurrentVersionExpiration.NoncurrentDays),
+			}
+		}
+		if rule.AbortIncompleteMultipartUpload.DaysAfterInitiation > 0 {
+			r.AbortIncompleteMultipart = &AbortIncompleteMultipartUpload{
+				DaysAfterInitiation: int(rule.AbortIncompleteMultipartUpload.DaysAfterInitiation),
+			}
+		}
+		cfg.Rules = append(cfg.Rules, r)
+	}
+	return cfg
+}
+
+func parseLifecycleDate(s string) (miniolc.ExpirationDate, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return miniolc.ExpirationDate{}, fmt.Errorf("parse date %q: %w", s, err)
+	}
+	return miniolc.ExpirationDate{Time: t}, nil
+}
+
+func formatLifecycleDate(d miniolc.ExpirationDate) string {
+	return d.Format(lifecycleDateLayout)
+}
@@ -0,0 +1,213 @@
+package lifecycle_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"codeberg.org/gruf/go-storage/s3"
+
+	"github.com/bltavares/aricanduva/pkg/lifecycle"
+)
+
+// fakeTransport is a minio-go http.RoundTripper stand-in that records the
+// last request it served and replays a canned status/body for every request.
+type fakeTransport struct {
+	status int
+	body   string
+
+	lastRequest *http.Request
+	lastBody    string
+}
+
+func (f *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.lastRequest = req
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		f.lastBody = string(body)
+	}
+	return &http.Response{
+		StatusCode: f.status,
+		Status:     http.StatusText(f.status),
+		Body:       io.NopCloser(strings.NewReader(f.body)),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func newFakeStorage(t *testing.T, transport *fakeTransport) *s3.Storage {
+	t.Helper()
+	storage, err := s3.Open("minio.test:9000", "fake-bucket", &s3.Config{
+		CoreOpts: minio.Options{
+			Creds:     credentials.NewStaticV4("id", "secret", ""),
+			Secure:    false,
+			Transport: transport,
+		},
+	})
+	if err != nil {
+		t.Fatalf("open storage: %v", err)
+	}
+	return storage
+}
+
+func TestApply(t *testing.T) {
+	transport := &fakeTransport{status: http.StatusOK}
+	storage := newFakeStorage(t, transport)
+
+	cfg := &lifecycle.Config{
+		Rules: []lifecycle.Rule{
+			{
+				ID:         "expire-temp",
+				Prefix:     "temp/",
+				Status:     "Enabled",
+				Expiration: &lifecycle.Expiration{Days: 7},
+			},
+		},
+	}
+
+	if err := lifecycle.Apply(context.Background(), storage, cfg); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	if transport.lastRequest == nil || transport.lastRequest.Method != http.MethodPut {
+		t.Fatalf("expected a PUT request, got %v", transport.lastRequest)
+	}
+	if !strings.Contains(transport.lastBody, "expire-temp") {
+		t.Errorf("expected request body to contain rule ID, got %q", transport.lastBody)
+	}
+}
+
+func TestApplyWithDate(t *testing.T) {
+	transport := &fakeTransport{status: http.StatusOK}
+	storage := newFakeStorage(t, transport)
+
+	cfg := &lifecycle.Config{
+		Rules: []lifecycle.Rule{
+			{
+				ID:         "expire-on-date",
+				Status:     "Enabled",
+				Expiration: &lifecycle.Expiration{Date: "2030-01-01T00:00:00.000Z"},
+			},
+		},
+	}
+
+	if err := lifecycle.Apply(context.Background(), storage, cfg); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if !strings.Contains(transport.lastBody, "2030-01-01") {
+		t.Errorf("expected request body to contain the expiration date, got %q", transport.lastBody)
+	}
+}
+
+func TestApplyRejectsDaysAndDate(t *testing.T) {
+	transport := &fakeTransport{status: http.StatusOK}
+	storage := newFakeStorage(t, transport)
+
+	cfg := &lifecycle.Config{
+		Rules: []lifecycle.Rule{
+			{
+				ID:         "conflicting",
+				Status:     "Enabled",
+				Expiration: &lifecycle.Expiration{Days: 7, Date: "2030-01-01T00:00:00.000Z"},
+			},
+		},
+	}
+
+	if err := lifecycle.Apply(context.Background(), storage, cfg); err == nil {
+		t.Fatalf("expected apply to reject a rule with both days and date set")
+	}
+	if transport.lastRequest != nil {
+		t.Errorf("expected validation to fail before any request was sent, got %v", transport.lastRequest)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	const body = `<?xml version="1.0" encoding="UTF-8"?>
+<LifecycleConfiguration>
+  <Rule>
+    <ID>expire-temp</ID>
+    <Filter><Prefix>temp/</Prefix></Filter>
+    <Status>Enabled</Status>
+    <Expiration><Days>7</Days></Expiration>
+  </Rule>
+</LifecycleConfiguration>`
+
+	transport := &fakeTransport{status: http.StatusOK, body: body}
+	storage := newFakeStorage(t, transport)
+
+	cfg, err := lifecycle.Fetch(context.Background(), storage)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if len(cfg.Rules) != 1 || cfg.Rules[0].ID != "expire-temp" {
+		t.Fatalf("unexpected config: %+v", cfg.Rules)
+	}
+	if cfg.Rules[0].Expiration == nil || cfg.Rules[0].Expiration.Days != 7 {
+		t.Fatalf("unexpected expiration: %+v", cfg.Rules[0].Expiration)
+	}
+}
+
+func TestFetchWithDate(t *testing.T) {
+	const body = `<?xml version="1.0" encoding="UTF-8"?>
+<LifecycleConfiguration>
+  <Rule>
+    <ID>expire-on-date</ID>
+    <Filter><Prefix></Prefix></Filter>
+    <Status>Enabled</Status>
+    <Expiration><Date>2030-01-01T00:00:00.000Z</Date></Expiration>
+  </Rule>
+</LifecycleConfiguration>`
+
+	transport := &fakeTransport{status: http.StatusOK, body: body}
+	storage := newFakeStorage(t, transport)
+
+	cfg, err := lifecycle.Fetch(context.Background(), storage)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if len(cfg.Rules) != 1 {
+		t.Fatalf("unexpected config: %+v", cfg.Rules)
+	}
+	exp := cfg.Rules[0].Expiration
+	if exp == nil || exp.Date == "" || exp.Days != 0 {
+		t.Fatalf("expected a date-only expiration, got %+v", exp)
+	}
+	if !strings.HasPrefix(exp.Date, "2030-01-01") {
+		t.Errorf("expected expiration date to round-trip, got %q", exp.Date)
+	}
+}
+
+// TestDiffIgnoresPointerIdentity guards against regressing to comparing
+// Rule values with != , which compares pointer fields by identity rather
+// than by the values they point to.
+func TestDiffIgnoresPointerIdentity(t *testing.T) {
+	current := &lifecycle.Config{
+		Rules: []lifecycle.Rule{
+			{ID: "a", Status: "Enabled", Expiration: &lifecycle.Expiration{Days: 7}},
+		},
+	}
+	desired := &lifecycle.Config{
+		Rules: []lifecycle.Rule{
+			// Same values as current, but a distinct *Expiration allocation.
+			{ID: "a", Status: "Enabled", Expiration: &lifecycle.Expiration{Days: 7}},
+		},
+	}
+
+	plan := lifecycle.Diff(current, desired)
+	if len(plan.Added) != 0 {
+		t.Fatalf("expected no rules to be reported as added, got %v", plan.Added)
+	}
+	if len(plan.Unchanged) != 1 {
+		t.Fatalf("expected rule %q to be reported unchanged, got %+v", "a", plan)
+	}
+
+	desired.Rules[0].Expiration.Days = 14
+	plan = lifecycle.Diff(current, desired)
+	if len(plan.Added) != 1 {
+		t.Fatalf("expected changed rule to be reported as added, got %+v", plan)
+	}
+}
@@ -0,0 +1,129 @@
+// Package query implements streaming SQL-style queries over objects stored
+// via go-storage's s3.Storage, built on minio-go's S3 Select support.
+package query
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+
+	"codeberg.org/gruf/go-storage/s3"
+)
+
+// Format identifies the serialization of an object's content.
+type Format int
+
+const (
+	FormatCSV Format = iota
+	FormatJSON
+	FormatParquet
+)
+
+// Options configures how an object is read and how results are returned.
+type Options struct {
+	// InputFormat is the serialization of the stored object.
+	InputFormat Format
+	// OutputFormat is the serialization results are streamed back in.
+	// Parquet is not a valid output format and falls back to CSV.
+	OutputFormat Format
+
+	// CompressionType is the compression applied to the stored object, if any.
+	CompressionType minio.SelectCompressionType
+	// FieldDelimiter separates fields for CSV input/output. Defaults to ",".
+	FieldDelimiter string
+	// RecordDelimiter separates records for CSV input/output and JSON
+	// output. Defaults to "\n".
+	RecordDelimiter string
+	// JSONType is the JSON layout for JSON input. Defaults to DOCUMENT.
+	JSONType minio.JSONType
+}
+
+func (o Options) fieldDelimiter() string {
+	if o.FieldDelimiter == "" {
+		return ","
+	}
+	return o.FieldDelimiter
+}
+
+func (o Options) recordDelimiter() string {
+	if o.RecordDelimiter == "" {
+		return "\n"
+	}
+	return o.RecordDelimiter
+}
+
+func (o Options) jsonType() minio.JSONType {
+	if o.JSONType == "" {
+		return minio.JSONDocumentType
+	}
+	return o.JSONType
+}
+
+func (o Options) inputSerialization() (minio.SelectObjectInputSerialization, error) {
+	in := minio.SelectObjectInputSerialization{
+		CompressionType: o.CompressionType,
+	}
+	switch o.InputFormat {
+	case FormatCSV:
+		in.CSV = &minio.CSVInputOptions{
+			FieldDelimiter:  o.fieldDelimiter(),
+			RecordDelimiter: o.recordDelimiter(),
+			FileHeaderInfo:  minio.CSVFileHeaderInfoUse,
+		}
+	case FormatJSON:
+		in.JSON = &minio.JSONInputOptions{Type: o.jsonType()}
+	case FormatParquet:
+		in.Parquet = &minio.ParquetInputOptions{}
+	default:
+		return in, fmt.Errorf("query: unsupported input format %v", o.InputFormat)
+	}
+	return in, nil
+}
+
+func (o Options) outputSerialization() minio.SelectObjectOutputSerialization {
+	out := minio.SelectObjectOutputSerialization{}
+	if o.OutputFormat == FormatJSON {
+		out.JSON = &minio.JSONOutputOptions{RecordDelimiter: o.recordDelimiter()}
+		return out
+	}
+	out.CSV = &minio.CSVOutputOptions{
+		FieldDelimiter:  o.fieldDelimiter(),
+		RecordDelimiter: o.recordDelimiter(),
+	}
+	return out
+}
+
+// Querier runs S3 Select queries against objects in a single bucket.
+type Querier struct {
+	storage *s3.Storage
+}
+
+// New returns a Querier that runs queries against objects stored through storage.
+func New(storage *s3.Storage) *Querier {
+	return &Querier{storage: storage}
+}
+
+// Query runs expr (a SQL-style SELECT statement) against the object at key
+// and streams back the matched records without fetching the whole object.
+// The caller must Close the returned reader.
+func (q *Querier) Query(ctx context.Context, key, expr string, opts Options) (io.ReadCloser, error) {
+	in, err := opts.inputSerialization()
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := q.storage.Client().SelectObjectContent(ctx, q.storage.Bucket(), key, minio.SelectObjectOptions{
+		Expression:     expr,
+		ExpressionType: minio.QueryExpressionTypeSQL,
+
+		InputSerialization:  in,
+		OutputSerialization: opts.outputSerialization(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query: select %s: %w", key, err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,54 @@
+package query_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/bltavares/aricanduva/internal/testutil"
+	"github.com/bltavares/aricanduva/pkg/query"
+)
+
+func TestQueryRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	const bucket = "query-test"
+	endpoint := testutil.StartMinio(t, nil)
+	storage := testutil.OpenStorage(t, endpoint, bucket)
+	testutil.MakeBucket(t, storage, bucket)
+
+	const key = "people.csv"
+	const csv = "name,age\nann,30\nbob,12\ncleo,45\n"
+	if _, err := storage.PutObject(ctx, key, bytes.NewBufferString(csv), minio.PutObjectOptions{
+		ContentType: "text/csv",
+	}); err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+
+	results, err := query.New(storage).Query(ctx, key, "SELECT s.name FROM S3Object s WHERE CAST(s.age AS INT) > 18", query.Options{
+		InputFormat:  query.FormatCSV,
+		OutputFormat: query.FormatCSV,
+	})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	defer results.Close()
+
+	out, err := io.ReadAll(results)
+	if err != nil {
+		t.Fatalf("read results: %v", err)
+	}
+
+	got := string(out)
+	for _, want := range []string{"ann", "cleo"} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("expected results to contain %q, got %q", want, got)
+		}
+	}
+	if bytes.Contains(out, []byte("bob")) {
+		t.Errorf("expected results to exclude bob (age 12), got %q", got)
+	}
+}
@@ -0,0 +1,120 @@
+// Package retention adds WORM-style object retention and legal hold support
+// on top of the go-storage S3 wrapper, mapping to minio-go's object lock
+// endpoints.
+package retention
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"context"
+
+	"github.com/minio/minio-go/v7"
+
+	"codeberg.org/gruf/go-storage/s3"
+)
+
+// Mode is a WORM retention mode.
+type Mode string
+
+const (
+	// ModeGovernance allows users with special permissions to overwrite or
+	// delete the object, or alter its lock settings.
+	ModeGovernance Mode = Mode(minio.Governance)
+	// ModeCompliance prevents the object from being overwritten or deleted
+	// by any user, including the root account, until the retention period
+	// expires.
+	ModeCompliance Mode = Mode(minio.Compliance)
+)
+
+// PutWithRetention uploads r to key with the given retention mode in effect
+// until retainUntil.
+func PutWithRetention(ctx context.Context, storage *s3.Storage, key string, r io.Reader, mode Mode, retainUntil time.Time, opts minio.PutObjectOptions) (minio.UploadInfo, error) {
+	opts.Mode = minio.RetentionMode(mode)
+	opts.RetainUntilDate = retainUntil
+
+	info, err := storage.PutObject(ctx, key, r, opts)
+	if err != nil {
+		return info, fmt.Errorf("retention: put %s with retention: %w", key, err)
+	}
+	return info, nil
+}
+
+// PutLegalHold enables or disables a legal hold on the object at key.
+func PutLegalHold(ctx context.Context, storage *s3.Storage, key string, on bool) error {
+	status := minio.LegalHoldDisabled
+	if on {
+		status = minio.LegalHoldEnabled
+	}
+
+	err := storage.Client().PutObjectLegalHold(ctx, storage.Bucket(), key, minio.PutObjectLegalHoldOptions{
+		Status: &status,
+	})
+	if err != nil {
+		return fmt.Errorf("retention: put legal hold on %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetRetention returns the retention mode and retain-until date currently
+// set on the object at key.
+func GetRetention(ctx context.Context, storage *s3.Storage, key string) (Mode, time.Time, error) {
+	mode, retainUntil, err := storage.Client().GetObjectRetention(ctx, storage.Bucket(), key, "")
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("retention: get retention on %s: %w", key, err)
+	}
+
+	var m Mode
+	if mode != nil {
+		m = Mode(*mode)
+	}
+	var until time.Time
+	if retainUntil != nil {
+		until = *retainUntil
+	}
+	return m, until, nil
+}
+
+// BypassGovernanceDelete deletes the object at key, bypassing GOVERNANCE mode
+// retention. The caller must have the s3:BypassGovernanceRetention
+// permission; COMPLIANCE mode retention cannot be bypassed.
+func BypassGovernanceDelete(ctx context.Context, storage *s3.Storage, key string) error {
+	err := storage.Client().RemoveObject(ctx, storage.Bucket(), key, minio.RemoveObjectOptions{
+		GovernanceBypass: true,
+	})
+	if err != nil {
+		return fmt.Errorf("retention: bypass governance delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// DefaultRetention configures the retention mode and validity period a
+// bucket applies to objects by default.
+type DefaultRetention struct {
+	Mode     Mode
+	Validity uint
+	Unit     minio.ValidityUnit
+}
+
+// MakeBucketWithLock creates bucket in region with object locking enabled,
+// optionally applying a default per-bucket retention configuration.
+func MakeBucketWithLock(ctx context.Context, client *minio.Client, bucket, region string, def *DefaultRetention) error {
+	err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{
+		Region:        region,
+		ObjectLocking: true,
+	})
+	if err != nil {
+		return fmt.Errorf("retention: make bucket %s with lock: %w", bucket, err)
+	}
+
+	if def != nil {
+		mode := minio.RetentionMode(def.Mode)
+		validity := def.Validity
+		unit := def.Unit
+		if err := client.SetBucketObjectLockConfig(ctx, bucket, &mode, &validity, &unit); err != nil {
+			return fmt.Errorf("retention: set default lock config on %s: %w", bucket, err)
+		}
+	}
+	return nil
+}
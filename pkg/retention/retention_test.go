@@ -0,0 +1,54 @@
+package retention_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/bltavares/aricanduva/internal/testutil"
+	"github.com/bltavares/aricanduva/pkg/retention"
+)
+
+const region = "us-east-1"
+
+func TestGovernanceDeleteRequiresBypass(t *testing.T) {
+	ctx := context.Background()
+
+	endpoint := testutil.StartMinio(t, map[string]string{"MINIO_REGION": region})
+	client := testutil.NewClient(t, endpoint)
+
+	const bucket = "retention-test"
+	if err := retention.MakeBucketWithLock(ctx, client, bucket, region, nil); err != nil {
+		t.Fatalf("make bucket with lock: %v", err)
+	}
+
+	storage := testutil.OpenStorage(t, endpoint, bucket)
+
+	const key = "locked.txt"
+	retainUntil := time.Now().Add(time.Hour)
+	if _, err := retention.PutWithRetention(ctx, storage, key, bytes.NewBufferString("hello"), retention.ModeGovernance, retainUntil, minio.PutObjectOptions{}); err != nil {
+		t.Fatalf("put with retention: %v", err)
+	}
+
+	mode, until, err := retention.GetRetention(ctx, storage, key)
+	if err != nil {
+		t.Fatalf("get retention: %v", err)
+	}
+	if mode != retention.ModeGovernance {
+		t.Errorf("expected mode %q, got %q", retention.ModeGovernance, mode)
+	}
+	if until.Before(time.Now()) {
+		t.Errorf("expected retain-until date in the future, got %v", until)
+	}
+
+	if err := client.RemoveObject(ctx, bucket, key, minio.RemoveObjectOptions{}); err == nil {
+		t.Fatalf("expected delete without bypass to be rejected")
+	}
+
+	if err := retention.BypassGovernanceDelete(ctx, storage, key); err != nil {
+		t.Fatalf("bypass governance delete: %v", err)
+	}
+}